@@ -0,0 +1,237 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package bboltstore
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Backup streams consistent snapshots of a *bolt.DB and compacts databases
+// to reclaim freelist space, using Tx.WriteTo under the hood so that
+// concurrent writes are not blocked.
+type Backup struct {
+	db         *bolt.DB
+	bufferSize int
+	txMaxSize  int64
+	logger     Logger
+}
+
+// NewBackup creates a Backup over db using the default buffer size and
+// compaction transaction size. Use WithBackupBufferSize and
+// WithCompactionTxMaxBytes to override them.
+func NewBackup(db *bolt.DB, options ...BackupOption) *Backup {
+	b := &Backup{
+		db:         db,
+		bufferSize: 32 * 1024,
+		txMaxSize:  64 * 1024 * 1024,
+		logger:     NopLogger,
+	}
+	for _, opt := range options {
+		opt.applyBackup(b)
+	}
+	return b
+}
+
+// BackupOption configures a Backup.
+type BackupOption interface {
+	applyBackup(*Backup)
+}
+
+type backupOptionFunc func(*Backup)
+
+func (fn backupOptionFunc) applyBackup(b *Backup) {
+	fn(b)
+}
+
+// WithBackupBufferSize sets the buffer size used while streaming a
+// snapshot out of the database, via Tx.WriteTo.
+func WithBackupBufferSize(value int) BackupOption {
+	return backupOptionFunc(func(b *Backup) {
+		b.bufferSize = value
+	})
+}
+
+// WithCompactionTxMaxBytes bounds how many bytes of key/value data Compact
+// rewrites per sub-transaction when copying into the destination database.
+func WithCompactionTxMaxBytes(value int64) BackupOption {
+	return backupOptionFunc(func(b *Backup) {
+		b.txMaxSize = value
+	})
+}
+
+// SnapshotTo writes a consistent, point-in-time copy of the database to w,
+// returning the number of bytes written. Writers to the source database
+// are not blocked while the snapshot streams.
+func (b *Backup) SnapshotTo(w io.Writer) (int64, error) {
+	var n int64
+	err := b.db.View(func(tx *bolt.Tx) error {
+		bw := bufio.NewWriterSize(w, b.bufferSize)
+		written, err := tx.WriteTo(bw)
+		n = written
+		if err != nil {
+			return err
+		}
+		return bw.Flush()
+	})
+	if err != nil {
+		b.logger.Errorf("bboltstore: snapshot failed after %d bytes: %v", n, err)
+	} else {
+		b.logger.Infof("bboltstore: snapshot wrote %d bytes", n)
+	}
+	return n, err
+}
+
+// SnapshotToFile is SnapshotTo, writing the snapshot to a new file at path
+// with the given permissions.
+func (b *Backup) SnapshotToFile(path string, perm os.FileMode) error {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := b.SnapshotTo(f); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+// Compact rewrites the database into a fresh file at dst, reclaiming space
+// held by the freelist and stale pages. It walks every bucket of the
+// source via a read-only transaction and copies key/values into dst in
+// sub-transactions bounded by txMaxSize bytes, the standard bbolt
+// compaction pattern. ctx cancellation is honored between buckets and
+// between sub-transactions, returning ErrCanceled.
+func (b *Backup) Compact(ctx context.Context, dst string, perm os.FileMode) error {
+	if err := checkCanceled(ctx); err != nil {
+		return err
+	}
+
+	dstDB, err := OpenDatabase(dst, perm)
+	if err != nil {
+		return err
+	}
+	defer dstDB.Close()
+
+	b.logger.Infof("bboltstore: compacting into %s", dst)
+	err = b.db.View(func(srcTx *bolt.Tx) error {
+		return srcTx.ForEach(func(name []byte, srcBucket *bolt.Bucket) error {
+			return compactBucket(ctx, dstDB, nil, name, srcBucket, b.txMaxSize)
+		})
+	})
+	if err != nil {
+		b.logger.Errorf("bboltstore: compaction into %s failed: %v", dst, err)
+	}
+	return err
+}
+
+// compactBucket copies srcBucket, found at path+name in the source, into
+// the same path+name in dst, recursing into nested buckets and flushing a
+// new write transaction every time the running total exceeds txMaxSize.
+func compactBucket(ctx context.Context, dst *bolt.DB, path [][]byte, name []byte, srcBucket *bolt.Bucket, txMaxSize int64) error {
+	if err := checkCanceled(ctx); err != nil {
+		return err
+	}
+
+	fullPath := append(append([][]byte{}, path...), name)
+
+	tx, err := dst.Begin(true)
+	if err != nil {
+		return err
+	}
+	dstBucket, err := createBucketPath(tx, fullPath)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var size int64
+	var children [][]byte
+	// commit flushes the current sub-transaction and opens the next one.
+	// It only reassigns tx/dstBucket once both the commit and the
+	// following Begin/createBucketPath have succeeded. If Commit fails,
+	// tx stays the transaction that failed to commit. If the following
+	// Begin or createBucketPath fails, the previous transaction is already
+	// committed, so tx is set to nil and ForEach's error path skips the
+	// rollback.
+	commit := func() error {
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		newTx, err := dst.Begin(true)
+		if err != nil {
+			tx = nil
+			return err
+		}
+		newBucket, err := createBucketPath(newTx, fullPath)
+		if err != nil {
+			newTx.Rollback()
+			tx = nil
+			return err
+		}
+		tx, dstBucket, size = newTx, newBucket, 0
+		return nil
+	}
+
+	err = srcBucket.ForEach(func(k, v []byte) error {
+		if err := checkCanceled(ctx); err != nil {
+			return err
+		}
+		if v == nil {
+			children = append(children, append([]byte(nil), k...))
+			return nil
+		}
+		if err := dstBucket.Put(k, v); err != nil {
+			return err
+		}
+		size += int64(len(k) + len(v))
+		if size >= txMaxSize {
+			return commit()
+		}
+		return nil
+	})
+	if err != nil {
+		if tx != nil {
+			tx.Rollback()
+		}
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	for _, child := range children {
+		if err := compactBucket(ctx, dst, fullPath, child, srcBucket.Bucket(child), txMaxSize); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createBucketPath creates (or reopens) every bucket along path within tx,
+// returning the innermost one.
+func createBucketPath(tx *bolt.Tx, path [][]byte) (*bolt.Bucket, error) {
+	var bucket *bolt.Bucket
+	for i, name := range path {
+		var b *bolt.Bucket
+		var err error
+		if i == 0 {
+			b, err = tx.CreateBucketIfNotExists(name)
+		} else {
+			b, err = bucket.CreateBucketIfNotExists(name)
+		}
+		if err != nil {
+			return nil, err
+		}
+		bucket = b
+	}
+	return bucket, nil
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package bboltstore
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestBackupCompact(t *testing.T) {
+	db := openTestDB(t)
+	store := NewBoltStore(db)
+
+	// Enough keys, spread over four buckets, to force Compact through
+	// several sub-transactions per bucket when txMaxSize is small.
+	for i := 0; i < 200; i++ {
+		key := []byte{'b', byte(i % 4), ':', 'k', byte(i / 4), byte(i % 4)}
+		if err := store.Set(key, bytes.Repeat([]byte{'x'}, 64)); err != nil {
+			t.Fatalf("set %d: %v", i, err)
+		}
+	}
+
+	backup := NewBackup(db, WithCompactionTxMaxBytes(512))
+	dst := filepath.Join(t.TempDir(), "compacted.db")
+	if err := backup.Compact(context.Background(), dst, 0600); err != nil {
+		t.Fatalf("compact: %v", err)
+	}
+
+	dstDB := openTestDBAt(t, dst)
+	dstStore := NewBoltStore(dstDB)
+	for i := 0; i < 200; i++ {
+		key := []byte{'b', byte(i % 4), ':', 'k', byte(i / 4), byte(i % 4)}
+		v, err := dstStore.Get(key)
+		if err != nil || len(v) != 64 {
+			t.Fatalf("get %d from compacted db: got %q, %v", i, v, err)
+		}
+	}
+}
+
+func TestBackupCompactHonorsCanceledContext(t *testing.T) {
+	db := openTestDB(t)
+	backup := NewBackup(db)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	dst := filepath.Join(t.TempDir(), "compacted.db")
+	if err := backup.Compact(ctx, dst, 0600); err != ErrCanceled {
+		t.Fatalf("expected ErrCanceled, got %v", err)
+	}
+}
+
+func openTestDBAt(t *testing.T, path string) *bolt.DB {
+	t.Helper()
+	db, err := bolt.Open(path, 0600, nil)
+	if err != nil {
+		t.Fatalf("open %s: %v", path, err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
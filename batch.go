@@ -0,0 +1,132 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package bboltstore
+
+import (
+	"sync/atomic"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// BatchMetrics reports throughput for a BatchWriter. All fields are
+// updated with atomic operations and are safe to read concurrently.
+type BatchMetrics struct {
+	transactions uint64
+	operations   uint64
+	started      time.Time
+}
+
+// Transactions is the number of distinct underlying DB.Batch transactions
+// that have been committed so far.
+func (m *BatchMetrics) Transactions() uint64 {
+	return atomic.LoadUint64(&m.transactions)
+}
+
+// Operations is the number of Put/Delete calls that have completed so far,
+// regardless of how many of them were coalesced into each transaction.
+func (m *BatchMetrics) Operations() uint64 {
+	return atomic.LoadUint64(&m.operations)
+}
+
+// TransactionsPerSecond is Transactions averaged over the lifetime of the
+// BatchWriter. A low rate relative to Operations indicates effective
+// coalescing.
+func (m *BatchMetrics) TransactionsPerSecond() float64 {
+	elapsed := time.Since(m.started).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(m.Transactions()) / elapsed
+}
+
+// BatchWriter fans concurrent Put/Delete calls into bbolt's DB.Batch so
+// that many independent callers share a single underlying transaction.
+// Use WithMaxBatchSize and WithMaxBatchDelay to tune how aggressively
+// bbolt coalesces them. Concurrency into the batch is bounded by
+// maxInFlight, which provides backpressure once the DB falls behind.
+type BatchWriter struct {
+	db              *bolt.DB
+	inFlight        chan struct{}
+	lastTxID        uint64
+	metrics         BatchMetrics
+	logger          Logger
+	slowTxThreshold time.Duration
+}
+
+// BatchOption configures a BatchWriter created by NewBatchWriter.
+type BatchOption interface {
+	applyBatch(*BatchWriter)
+}
+
+// NewBatchWriter creates a BatchWriter over db. maxInFlight bounds the
+// number of Put/Delete calls allowed to be queued into bbolt's batch
+// manager at once; additional callers block until a slot frees up. A
+// maxInFlight of 0 or less disables the bound.
+func NewBatchWriter(db *bolt.DB, maxInFlight int, options ...BatchOption) *BatchWriter {
+	w := &BatchWriter{db: db, metrics: BatchMetrics{started: time.Now()}, logger: NopLogger}
+	if maxInFlight > 0 {
+		w.inFlight = make(chan struct{}, maxInFlight)
+	}
+	for _, opt := range options {
+		opt.applyBatch(w)
+	}
+	return w
+}
+
+// Metrics returns the BatchWriter's live metrics.
+func (w *BatchWriter) Metrics() *BatchMetrics {
+	return &w.metrics
+}
+
+// Put sets key to value as part of a coalesced batch transaction.
+func (w *BatchWriter) Put(key, value []byte) error {
+	path, leaf := splitKey(key)
+	return w.do(func(tx *bolt.Tx) error {
+		bucket, err := bucketFor(tx, path, true)
+		if err != nil {
+			return err
+		}
+		return bucket.Put(leaf, value)
+	})
+}
+
+// Delete removes key as part of a coalesced batch transaction.
+func (w *BatchWriter) Delete(key []byte) error {
+	path, leaf := splitKey(key)
+	return w.do(func(tx *bolt.Tx) error {
+		bucket, err := bucketFor(tx, path, false)
+		if err != nil || bucket == nil {
+			return err
+		}
+		return bucket.Delete(leaf)
+	})
+}
+
+// do runs fn through DB.Batch, applying backpressure and recording
+// metrics. Errors are returned directly to the caller: bbolt re-runs fn
+// alone, outside of the shared batch, if it fails so that one caller's
+// error never poisons the others sharing the transaction.
+func (w *BatchWriter) do(fn func(*bolt.Tx) error) error {
+	if w.inFlight != nil {
+		w.inFlight <- struct{}{}
+		defer func() { <-w.inFlight }()
+	}
+
+	started := time.Now()
+	err := w.db.Batch(func(tx *bolt.Tx) error {
+		id := uint64(tx.ID())
+		if atomic.SwapUint64(&w.lastTxID, id) != id {
+			atomic.AddUint64(&w.metrics.transactions, 1)
+		}
+		return fn(tx)
+	})
+	atomic.AddUint64(&w.metrics.operations, 1)
+	if elapsed := time.Since(started); w.slowTxThreshold > 0 && elapsed > w.slowTxThreshold {
+		w.logger.Warnf("bboltstore: slow batch transaction took %s", elapsed)
+	}
+	return err
+}
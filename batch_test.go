@@ -0,0 +1,76 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package bboltstore
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestBatchWriterPutDelete(t *testing.T) {
+	db := openTestDB(t)
+	db.MaxBatchDelay = 0
+	writer := NewBatchWriter(db, 0)
+
+	if err := writer.Put([]byte("users:1:name"), []byte("alice")); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	store := NewBoltStore(db)
+	if v, err := store.Get([]byte("users:1:name")); err != nil || string(v) != "alice" {
+		t.Fatalf("get: got %q, %v", v, err)
+	}
+
+	if err := writer.Delete([]byte("users:1:name")); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if v, err := store.Get([]byte("users:1:name")); err != nil || v != nil {
+		t.Fatalf("expected deleted key to be gone, got %q, %v", v, err)
+	}
+
+	if got := writer.Metrics().Operations(); got != 2 {
+		t.Fatalf("expected 2 operations recorded, got %d", got)
+	}
+}
+
+// TestBatchWriterCoalescesConcurrentWrites checks that many concurrent
+// Put calls funneled through one BatchWriter all land, that every call is
+// counted as an operation, and that the transaction count is non-zero and
+// at most one per call.
+func TestBatchWriterCoalescesConcurrentWrites(t *testing.T) {
+	db := openTestDB(t)
+	db.MaxBatchSize = 100
+	writer := NewBatchWriter(db, 0)
+
+	const n = 50
+	var wg sync.WaitGroup
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func(i int) {
+			defer wg.Done()
+			key := []byte{'k', byte('0' + i/10), byte('0' + i%10)}
+			if err := writer.Put(key, []byte("v")); err != nil {
+				t.Errorf("put %d: %v", i, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	store := NewBoltStore(db)
+	for i := 0; i < n; i++ {
+		key := []byte{'k', byte('0' + i/10), byte('0' + i%10)}
+		if v, err := store.Get(key); err != nil || string(v) != "v" {
+			t.Fatalf("get %d: got %q, %v", i, v, err)
+		}
+	}
+
+	if got := writer.Metrics().Operations(); got != n {
+		t.Fatalf("expected %d operations recorded, got %d", n, got)
+	}
+	if got := writer.Metrics().Transactions(); got == 0 || got > n {
+		t.Fatalf("expected a plausible, non-zero transaction count, got %d", got)
+	}
+}
@@ -146,4 +146,59 @@ func WithMlock() Option {
 	})
 }
 
+// DBOption configures the *bolt.DB returned by OpenDatabase. Unlike Option,
+// which configures bolt.Options before the file is opened, DBOption applies
+// to fields that only exist on the DB itself, such as DB.MaxBatchSize.
+type DBOption interface {
+	applyDB(*bolt.DB)
+}
+
+// dbOptionFunc implements DBOption interface.
+type dbOptionFunc func(*bolt.DB)
+
+func (fn dbOptionFunc) applyDB(db *bolt.DB) {
+	fn(db)
+}
+
+// WithMaxBatchSize sets DB.MaxBatchSize, the maximum number of operations
+// coalesced into a single DB.Batch transaction before it is flushed early.
+// Bbolt's default is 1000. A value of 0 or less disables Batch entirely.
+func WithMaxBatchSize(value int) DBOption {
+	return dbOptionFunc(func(db *bolt.DB) {
+		db.MaxBatchSize = value
+	})
+}
+
+// WithMaxBatchDelay sets DB.MaxBatchDelay, the maximum amount of time a
+// DB.Batch transaction waits for more operations before it is flushed.
+// Bbolt's default is 10ms. A value of 0 or less disables the delay.
+func WithMaxBatchDelay(value time.Duration) DBOption {
+	return dbOptionFunc(func(db *bolt.DB) {
+		db.MaxBatchDelay = value
+	})
+}
+
+// WithStrictMode sets DB.StrictMode, causing every committed write
+// transaction to run Tx.Check() and panic if it finds corruption. This
+// catches bugs close to where they happen, at the cost of a full
+// consistency walk on every commit, so it is best reserved for tests
+// and diagnosing a suspected corruption rather than steady-state
+// production use.
+func WithStrictMode() DBOption {
+	return dbOptionFunc(func(db *bolt.DB) {
+		db.StrictMode = true
+	})
+}
+
+// WithAllocSize sets DB.AllocSize, the amount by which the database file
+// grows when it runs out of space. Bbolt's default is 16MiB; raising it
+// trades a larger file for fewer, cheaper growth operations on databases
+// that write a lot of data. It isn't exposed on bolt.Options, so it must
+// be set on the *bolt.DB after Open.
+func WithAllocSize(value int) DBOption {
+	return dbOptionFunc(func(db *bolt.DB) {
+		db.AllocSize = value
+	})
+}
+
 
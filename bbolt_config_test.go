@@ -0,0 +1,38 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package bboltstore
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// TestOpenDatabaseWithAppliesDBOptions verifies that DBOptions such as
+// WithAllocSize, which only exist on *bolt.DB rather than bolt.Options,
+// are actually applied by OpenDatabaseWith rather than silently ignored.
+func TestOpenDatabaseWithAppliesDBOptions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+
+	db, err := OpenDatabaseWith(path, 0600, nil, []DBOption{
+		WithAllocSize(1 << 20),
+		WithMaxBatchSize(42),
+		WithStrictMode(),
+	})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer db.Close()
+
+	if db.AllocSize != 1<<20 {
+		t.Fatalf("expected AllocSize to be set, got %d", db.AllocSize)
+	}
+	if db.MaxBatchSize != 42 {
+		t.Fatalf("expected MaxBatchSize to be set, got %d", db.MaxBatchSize)
+	}
+	if !db.StrictMode {
+		t.Fatalf("expected StrictMode to be set")
+	}
+}
@@ -21,6 +21,23 @@ func OpenDatabase(dataFile string, dataFilePerm os.FileMode, options ...Option)
 	return bolt.Open(dataFile, dataFilePerm, opts)
 }
 
+// OpenDatabaseWith is like OpenDatabase, but additionally applies DBOptions
+// to the returned *bolt.DB, for settings such as MaxBatchSize and
+// MaxBatchDelay that bbolt only exposes on DB itself, not on bolt.Options.
+func OpenDatabaseWith(dataFile string, dataFilePerm os.FileMode, options []Option, dbOptions []DBOption) (*bolt.DB, error) {
+
+	db, err := OpenDatabase(dataFile, dataFilePerm, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, opt := range dbOptions {
+		opt.applyDB(db)
+	}
+
+	return db, nil
+}
+
 func ObjectType() reflect.Type {
 	return BoltStoreClass
 }
@@ -0,0 +1,201 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package bboltstore
+
+import (
+	"bytes"
+	"context"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// EnumerateOptions controls a single Enumerate call.
+type EnumerateOptions struct {
+
+	// Context cancels the enumeration early. ErrCanceled is returned as
+	// soon as the cancellation is observed.
+	Context context.Context
+
+	// Prefix restricts enumeration to keys sharing this prefix. The
+	// bucket path encoded in Prefix (everything up to the last
+	// BucketSeparator) selects the Bucket being enumerated; the
+	// remainder filters the leaf keys inside it.
+	Prefix []byte
+
+	// Seek, if non-empty, positions the cursor at the first key greater
+	// than or equal to Seek instead of starting at the beginning (or end,
+	// if Reverse) of the Bucket. Seek must resolve to the same Bucket as
+	// Prefix, otherwise ErrInvalidSeek is returned.
+	Seek []byte
+
+	// Reverse iterates from the end of the Bucket towards the beginning.
+	Reverse bool
+}
+
+// ScanOptions controls a single Scan call.
+type ScanOptions struct {
+
+	// Context cancels the scan early. ErrCanceled is returned as soon as
+	// the cancellation is observed.
+	Context context.Context
+}
+
+// Enumerate iterates the leaf keys of the Bucket addressed by
+// opts.Prefix, invoking cb with the full, reconstructed key (including
+// its bucket path) and value for each one. Iteration stops when cb
+// returns false or an error, or when opts.Context is done.
+func (s *implBoltStore) Enumerate(opts EnumerateOptions, cb func(key, value []byte) (bool, error)) error {
+	path, localPrefix := splitKey(opts.Prefix)
+
+	hasSeek := len(opts.Seek) > 0
+	var seekLeaf []byte
+	if hasSeek {
+		var seekPath [][]byte
+		seekPath, seekLeaf = splitKey(opts.Seek)
+		if !bucketPathEqual(path, seekPath) {
+			return ErrInvalidSeek
+		}
+	}
+
+	return s.view(func(tx *bolt.Tx) error {
+		bucket, err := bucketFor(tx, path, false)
+		if err != nil || bucket == nil {
+			return err
+		}
+
+		cursor := bucket.Cursor()
+		var k, v []byte
+		switch {
+		case hasSeek:
+			// Seek always positions at the first key >= seekLeaf, even
+			// for Reverse: bbolt's Cursor has no SeekForPrev, so a
+			// reverse enumeration seeked to a bucket-boundary key (an
+			// empty seekLeaf) or any other key with nothing before it
+			// in the bucket may yield fewer results than expected.
+			k, v = cursor.Seek(seekLeaf)
+		case opts.Reverse:
+			k, v = cursor.Last()
+		default:
+			k, v = cursor.First()
+		}
+
+		for ; k != nil; k, v = advance(cursor, opts.Reverse) {
+			if err := checkCanceled(opts.Context); err != nil {
+				return err
+			}
+			if len(localPrefix) > 0 && !bytes.HasPrefix(k, localPrefix) {
+				if opts.Reverse {
+					continue
+				}
+				break
+			}
+			full := joinKey(path, k)
+			more, err := cb(full, v)
+			if err != nil {
+				return err
+			}
+			if !more {
+				return nil
+			}
+		}
+		return nil
+	})
+}
+
+// Scan walks every leaf key in the store, recursing into every nested
+// Bucket, invoking cb with the full, reconstructed key for each one.
+// Iteration stops when cb returns false or an error, or when
+// opts.Context is done.
+func (s *implBoltStore) Scan(opts ScanOptions, cb func(key []byte) (bool, error)) error {
+	return s.view(func(tx *bolt.Tx) error {
+		stop := false
+		err := tx.ForEach(func(name []byte, b *bolt.Bucket) error {
+			if stop {
+				return nil
+			}
+			more, err := scanBucket(opts.Context, [][]byte{name}, b, cb)
+			if err != nil {
+				return err
+			}
+			if !more {
+				stop = true
+			}
+			return nil
+		})
+		return err
+	})
+}
+
+func scanBucket(ctx context.Context, path [][]byte, bucket *bolt.Bucket, cb func(key []byte) (bool, error)) (bool, error) {
+	more := true
+	err := bucket.ForEach(func(k, v []byte) error {
+		if !more {
+			return nil
+		}
+		if err := checkCanceled(ctx); err != nil {
+			return err
+		}
+		if v == nil {
+			child := bucket.Bucket(k)
+			childMore, err := scanBucket(ctx, append(path, k), child, cb)
+			if err != nil {
+				return err
+			}
+			more = childMore
+			return nil
+		}
+		m, err := cb(joinKey(path, k))
+		if err != nil {
+			return err
+		}
+		more = m
+		return nil
+	})
+	return more, err
+}
+
+func advance(cursor *bolt.Cursor, reverse bool) ([]byte, []byte) {
+	if reverse {
+		return cursor.Prev()
+	}
+	return cursor.Next()
+}
+
+func joinKey(path [][]byte, leaf []byte) []byte {
+	key := make([]byte, 0, len(leaf)+8*len(path))
+	for _, segment := range path {
+		if bytes.Equal(segment, rootBucketSentinel) {
+			continue
+		}
+		key = append(key, segment[1:]...) // strip encodeSegment's tag byte
+		key = append(key, BucketSeparator)
+	}
+	return append(key, leaf...)
+}
+
+func bucketPathEqual(a, b [][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !bytes.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func checkCanceled(ctx context.Context) error {
+	if ctx == nil {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ErrCanceled
+	default:
+		return nil
+	}
+}
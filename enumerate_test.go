@@ -0,0 +1,200 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package bboltstore
+
+import (
+	"context"
+	"testing"
+)
+
+func collectEnumerate(t *testing.T, store BoltStore, opts EnumerateOptions) ([]string, error) {
+	t.Helper()
+	var keys []string
+	err := store.Enumerate(opts, func(key, value []byte) (bool, error) {
+		keys = append(keys, string(key))
+		return true, nil
+	})
+	return keys, err
+}
+
+// Enumerate lists the leaf keys of a single Bucket, so these tests use
+// keys with one separator ("users:1") rather than nested paths - the
+// Bucket addressed by the "users:" Prefix holds leaf keys "1", "2", "3"
+// directly.
+
+func TestEnumeratePrefixAndReverse(t *testing.T) {
+	store := NewBoltStore(openTestDB(t))
+
+	for _, key := range []string{"users:1", "users:2", "users:3", "groups:1"} {
+		if err := store.Set([]byte(key), []byte("v")); err != nil {
+			t.Fatalf("set %s: %v", key, err)
+		}
+	}
+
+	keys, err := collectEnumerate(t, store, EnumerateOptions{Prefix: []byte("users:")})
+	if err != nil {
+		t.Fatalf("enumerate: %v", err)
+	}
+	want := []string{"users:1", "users:2", "users:3"}
+	if !equalStrings(keys, want) {
+		t.Fatalf("got %v, want %v", keys, want)
+	}
+
+	keys, err = collectEnumerate(t, store, EnumerateOptions{Prefix: []byte("users:"), Reverse: true})
+	if err != nil {
+		t.Fatalf("enumerate reverse: %v", err)
+	}
+	wantReverse := []string{"users:3", "users:2", "users:1"}
+	if !equalStrings(keys, wantReverse) {
+		t.Fatalf("got %v, want %v", keys, wantReverse)
+	}
+}
+
+func TestEnumerateSeek(t *testing.T) {
+	store := NewBoltStore(openTestDB(t))
+
+	for _, key := range []string{"users:1", "users:2", "users:3"} {
+		if err := store.Set([]byte(key), []byte("v")); err != nil {
+			t.Fatalf("set %s: %v", key, err)
+		}
+	}
+
+	keys, err := collectEnumerate(t, store, EnumerateOptions{Prefix: []byte("users:"), Seek: []byte("users:2")})
+	if err != nil {
+		t.Fatalf("enumerate: %v", err)
+	}
+	want := []string{"users:2", "users:3"}
+	if !equalStrings(keys, want) {
+		t.Fatalf("got %v, want %v", keys, want)
+	}
+}
+
+// TestEnumerateSeekToEmptyLeaf guards against a Seek that resolves to an
+// empty leaf - a bucket-boundary seek like Seek: []byte("users:") - being
+// silently dropped in favor of First()/Last().
+func TestEnumerateSeekToEmptyLeaf(t *testing.T) {
+	store := NewBoltStore(openTestDB(t))
+
+	for _, key := range []string{"users:1", "users:2"} {
+		if err := store.Set([]byte(key), []byte("v")); err != nil {
+			t.Fatalf("set %s: %v", key, err)
+		}
+	}
+
+	keys, err := collectEnumerate(t, store, EnumerateOptions{
+		Prefix:  []byte("users:"),
+		Seek:    []byte("users:"),
+		Reverse: true,
+	})
+	if err != nil {
+		t.Fatalf("enumerate: %v", err)
+	}
+	want := []string{"users:1"}
+	if !equalStrings(keys, want) {
+		t.Fatalf("got %v, want %v", keys, want)
+	}
+}
+
+func TestEnumerateInvalidSeek(t *testing.T) {
+	store := NewBoltStore(openTestDB(t))
+
+	if err := store.Set([]byte("users:1"), []byte("v")); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	_, err := collectEnumerate(t, store, EnumerateOptions{Prefix: []byte("users:"), Seek: []byte("groups:1")})
+	if err != ErrInvalidSeek {
+		t.Fatalf("expected ErrInvalidSeek, got %v", err)
+	}
+}
+
+func TestEnumerateHonorsCanceledContext(t *testing.T) {
+	store := NewBoltStore(openTestDB(t))
+
+	if err := store.Set([]byte("users:1"), []byte("v")); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := collectEnumerate(t, store, EnumerateOptions{Prefix: []byte("users:"), Context: ctx})
+	if err != ErrCanceled {
+		t.Fatalf("expected ErrCanceled, got %v", err)
+	}
+}
+
+func TestScan(t *testing.T) {
+	store := NewBoltStore(openTestDB(t))
+
+	for _, key := range []string{"users:1:name", "users:2:name", "flat"} {
+		if err := store.Set([]byte(key), []byte("v")); err != nil {
+			t.Fatalf("set %s: %v", key, err)
+		}
+	}
+
+	var keys []string
+	err := store.Scan(ScanOptions{}, func(key []byte) (bool, error) {
+		keys = append(keys, string(key))
+		return true, nil
+	})
+	if err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	want := []string{"flat", "users:1:name", "users:2:name"}
+	if !equalStringSets(keys, want) {
+		t.Fatalf("got %v, want %v", keys, want)
+	}
+}
+
+func TestScanHonorsCanceledContext(t *testing.T) {
+	store := NewBoltStore(openTestDB(t))
+
+	if err := store.Set([]byte("users:1:name"), []byte("v")); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := store.Scan(ScanOptions{Context: ctx}, func(key []byte) (bool, error) {
+		return true, nil
+	})
+	if err != ErrCanceled {
+		t.Fatalf("expected ErrCanceled, got %v", err)
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func equalStringSets(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[string]int, len(b))
+	for _, v := range b {
+		seen[v]++
+	}
+	for _, v := range a {
+		seen[v]--
+	}
+	for _, n := range seen {
+		if n != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package bboltstore
+
+import "time"
+
+// Logger is a minimal structured logging interface used by the Store,
+// BatchWriter, Backup and Verifier subsystems, and by OpenDatabaseContext,
+// to report slow transactions, lock-acquisition retries and similar
+// operational events. It is intentionally small so that adapting an
+// application's existing logger is a few lines of glue; see
+// NewSlogLogger for a ready-made adapter.
+//
+// bbolt itself (as of v1.3.9, the version this package builds against)
+// has no pluggable logger of its own, so there is no way to also
+// receive bbolt's internal messages (e.g. mmap remap events) through
+// this interface; it only covers events bboltstore generates directly.
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Debugf(string, ...interface{}) {}
+func (nopLogger) Infof(string, ...interface{})  {}
+func (nopLogger) Warnf(string, ...interface{})  {}
+func (nopLogger) Errorf(string, ...interface{}) {}
+
+// NopLogger discards every message. It is the default Logger used by
+// every subsystem until WithLogger is supplied.
+var NopLogger Logger = nopLogger{}
+
+// LoggerOption configures the Logger used by a Store, BatchWriter,
+// Backup, Verifier, or OpenDatabaseContext's lock-retry loop.
+type LoggerOption interface {
+	OpenOption
+	StoreOption
+	BatchOption
+	BackupOption
+	VerifierOption
+}
+
+type loggerOption struct {
+	logger Logger
+}
+
+func (o loggerOption) applyOpen(c *openConfig)     { c.logger = o.logger }
+func (o loggerOption) applyStore(s *implBoltStore) { s.logger = o.logger }
+func (o loggerOption) applyBatch(w *BatchWriter)   { w.logger = o.logger }
+func (o loggerOption) applyBackup(b *Backup)       { b.logger = o.logger }
+func (o loggerOption) applyVerifier(v *Verifier)   { v.logger = o.logger }
+
+// WithLogger installs l on a Store, BatchWriter, Backup, Verifier or
+// OpenDatabaseContext call, which use it to report slow transactions,
+// lock-acquisition retries and similar operational events. The default
+// is NopLogger.
+func WithLogger(l Logger) LoggerOption {
+	return loggerOption{logger: l}
+}
+
+// SlowTxOption configures the slow-transaction threshold of a Store or
+// BatchWriter.
+type SlowTxOption interface {
+	StoreOption
+	BatchOption
+}
+
+type slowTxOption struct {
+	threshold time.Duration
+}
+
+func (o slowTxOption) applyStore(s *implBoltStore) { s.slowTxThreshold = o.threshold }
+func (o slowTxOption) applyBatch(w *BatchWriter)   { w.slowTxThreshold = o.threshold }
+
+// WithSlowTxThreshold configures a Store or BatchWriter to log, via its
+// Logger at Warnf level, any single transaction that takes longer than
+// threshold to complete. A threshold of 0 (the default) disables the
+// check.
+func WithSlowTxThreshold(threshold time.Duration) SlowTxOption {
+	return slowTxOption{threshold: threshold}
+}
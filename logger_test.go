@@ -0,0 +1,55 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package bboltstore
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingLogger is a Logger that records every message passed to Warnf,
+// for asserting that a Logger installed via WithLogger actually receives
+// the events it was installed to observe.
+type recordingLogger struct {
+	mu    sync.Mutex
+	warns []string
+}
+
+func (l *recordingLogger) Debugf(format string, args ...interface{}) {}
+func (l *recordingLogger) Infof(format string, args ...interface{})  {}
+func (l *recordingLogger) Warnf(format string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.warns = append(l.warns, fmt.Sprintf(format, args...))
+}
+func (l *recordingLogger) Errorf(format string, args ...interface{}) {}
+
+func (l *recordingLogger) warnCount() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.warns)
+}
+
+// TestStoreLogsSlowTransactions verifies that WithLogger, combined with
+// WithSlowTxThreshold, is actually wired into the Store's transactions
+// rather than just stored and never consulted.
+func TestStoreLogsSlowTransactions(t *testing.T) {
+	logger := &recordingLogger{}
+	store := NewBoltStore(openTestDB(t), WithLogger(logger), WithSlowTxThreshold(time.Nanosecond))
+
+	if err := store.Set([]byte("a"), []byte("b")); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if _, err := store.Get([]byte("a")); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+
+	if logger.warnCount() == 0 {
+		t.Fatalf("expected slow transactions to be logged with a near-zero threshold")
+	}
+}
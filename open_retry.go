@@ -0,0 +1,98 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package bboltstore
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// OpenOption configures the retry behavior of OpenDatabaseContext, as
+// opposed to Option (bolt.Options, applied before Open) or DBOption
+// (*bolt.DB, applied after Open).
+type OpenOption interface {
+	applyOpen(*openConfig)
+}
+
+type openConfig struct {
+	lockRetryTimeout time.Duration
+	logger           Logger
+}
+
+type openOptionFunc func(*openConfig)
+
+func (fn openOptionFunc) applyOpen(c *openConfig) {
+	fn(c)
+}
+
+// WithLockRetryTimeout causes OpenDatabaseContext to retry after this
+// interval when bolt.Open fails because another process holds the
+// database's file lock, instead of returning the error immediately.
+// Retries continue until Open succeeds, the context passed to
+// OpenDatabaseContext is done (in which case ErrCanceled is returned), or
+// Open fails for a reason other than a lock timeout. The default, zero,
+// disables retrying.
+//
+// bbolt only ever fails bolt.Open with bolt.ErrTimeout - the error this
+// retries on - when Options.Timeout (see WithTimeout) is itself
+// non-zero; with the default zero Timeout, bolt.Open blocks inside the OS
+// file lock call indefinitely instead of returning an error to retry on.
+// So that WithLockRetryTimeout is not silently inert unless the caller
+// separately remembers WithTimeout, OpenDatabaseContext defaults
+// Options.Timeout to interval whenever the caller did not already set one.
+func WithLockRetryTimeout(interval time.Duration) OpenOption {
+	return openOptionFunc(func(c *openConfig) {
+		c.lockRetryTimeout = interval
+	})
+}
+
+// OpenDatabaseContext is OpenDatabase with support for OpenOptions and
+// cancellation via ctx while waiting to acquire the database's file
+// lock, for graceful shutdown when multiple processes contend for the
+// same file.
+func OpenDatabaseContext(ctx context.Context, dataFile string, dataFilePerm os.FileMode, options []Option, openOptions []OpenOption) (*bolt.DB, error) {
+	cfg := &openConfig{logger: NopLogger}
+	for _, opt := range openOptions {
+		opt.applyOpen(cfg)
+	}
+
+	if cfg.lockRetryTimeout > 0 {
+		opts := &bolt.Options{}
+		for _, opt := range options {
+			opt.apply(opts)
+		}
+		if opts.Timeout <= 0 {
+			options = append(append([]Option{}, options...), WithTimeout(cfg.lockRetryTimeout))
+		}
+	}
+
+	for {
+		db, err := OpenDatabase(dataFile, dataFilePerm, options...)
+		if err == nil {
+			return db, nil
+		}
+		if cfg.lockRetryTimeout <= 0 || !errors.Is(err, bolt.ErrTimeout) {
+			return nil, err
+		}
+		if err := checkCanceled(ctx); err != nil {
+			return nil, err
+		}
+		cfg.logger.Warnf("bboltstore: %s locked, retrying in %s", dataFile, cfg.lockRetryTimeout)
+		if ctx == nil {
+			time.Sleep(cfg.lockRetryTimeout)
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ErrCanceled
+		case <-time.After(cfg.lockRetryTimeout):
+		}
+	}
+}
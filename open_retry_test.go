@@ -0,0 +1,76 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package bboltstore
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// TestOpenDatabaseContextRetriesUntilUnlocked verifies that
+// WithLockRetryTimeout alone - without the caller separately passing
+// WithTimeout - is enough for OpenDatabaseContext to retry past another
+// holder of the file lock and eventually succeed once it is released.
+func TestOpenDatabaseContextRetriesUntilUnlocked(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+
+	holder := openTestDBAt(t, path)
+
+	done := make(chan struct{})
+	var db *bolt.DB
+	var err error
+	go func() {
+		defer close(done)
+		db, err = OpenDatabaseContext(context.Background(), path, 0600, nil,
+			[]OpenOption{WithLockRetryTimeout(20 * time.Millisecond)})
+	}()
+
+	select {
+	case <-done:
+		t.Fatalf("OpenDatabaseContext returned before the lock was released")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	if closeErr := holder.Close(); closeErr != nil {
+		t.Fatalf("close holder: %v", closeErr)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("OpenDatabaseContext did not return after the lock was released")
+	}
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	db.Close()
+}
+
+// TestOpenDatabaseContextHonorsCanceledContext verifies that a context
+// canceled while retrying stops OpenDatabaseContext with ErrCanceled
+// instead of retrying forever.
+func TestOpenDatabaseContextHonorsCanceledContext(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+
+	holder := openTestDBAt(t, path)
+	defer holder.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+
+	_, err := OpenDatabaseContext(ctx, path, 0600, nil,
+		[]OpenOption{WithLockRetryTimeout(20 * time.Millisecond)})
+	if err != ErrCanceled {
+		t.Fatalf("expected ErrCanceled, got %v", err)
+	}
+}
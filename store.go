@@ -0,0 +1,246 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package bboltstore
+
+import (
+	"bytes"
+	"reflect"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// BoltStore is a hierarchical key-value facade over a *bolt.DB. Keys are
+// byte slices where occurrences of BucketSeparator split the key into a
+// path of nested Buckets, with the last segment being the actual key
+// stored inside the innermost bucket. For example "users:42:profile" is
+// stored as key "profile" inside bucket "42" inside bucket "users".
+type BoltStore interface {
+
+	// Get looks up key and returns nil, nil if it does not exist.
+	Get(key []byte) (value []byte, err error)
+
+	// Set creates or overwrites the value for key, creating any
+	// intermediate buckets along the way.
+	Set(key, value []byte) error
+
+	// CompareAndSwap atomically replaces the value stored at key with
+	// newValue if and only if the current value equals oldValue. A nil
+	// oldValue matches a missing key. It reports whether the swap happened.
+	CompareAndSwap(key, oldValue, newValue []byte) (swapped bool, err error)
+
+	// Delete removes key. It is not an error to delete a missing key.
+	Delete(key []byte) error
+
+	// Enumerate iterates over the keys of the Bucket addressed by prefix,
+	// see Enumerate function doc for details.
+	Enumerate(opts EnumerateOptions, cb func(key, value []byte) (bool, error)) error
+
+	// Scan walks every key in the store, regardless of bucket nesting,
+	// see Scan function doc for details.
+	Scan(opts ScanOptions, cb func(key []byte) (bool, error)) error
+
+	// Close closes the underlying *bolt.DB.
+	Close() error
+}
+
+var BoltStoreClass = reflect.TypeOf((*BoltStore)(nil)).Elem()
+
+type implBoltStore struct {
+	db              *bolt.DB
+	logger          Logger
+	slowTxThreshold time.Duration
+}
+
+// StoreOption configures a BoltStore created by NewBoltStore.
+type StoreOption interface {
+	applyStore(*implBoltStore)
+}
+
+// NewBoltStore wraps an already opened *bolt.DB (see OpenDatabase) with the
+// hierarchical BoltStore API.
+func NewBoltStore(db *bolt.DB, options ...StoreOption) BoltStore {
+	s := &implBoltStore{db: db, logger: NopLogger}
+	for _, opt := range options {
+		opt.applyStore(s)
+	}
+	return s
+}
+
+// view runs fn in a read-only transaction, logging it as slow if it
+// exceeds s.slowTxThreshold.
+func (s *implBoltStore) view(fn func(*bolt.Tx) error) error {
+	started := time.Now()
+	err := s.db.View(fn)
+	s.logSlowTx("view", time.Since(started))
+	return err
+}
+
+// update runs fn in a read-write transaction, logging it as slow if it
+// exceeds s.slowTxThreshold.
+func (s *implBoltStore) update(fn func(*bolt.Tx) error) error {
+	started := time.Now()
+	err := s.db.Update(fn)
+	s.logSlowTx("update", time.Since(started))
+	return err
+}
+
+func (s *implBoltStore) logSlowTx(kind string, elapsed time.Duration) {
+	if s.slowTxThreshold > 0 && elapsed > s.slowTxThreshold {
+		s.logger.Warnf("bboltstore: slow %s transaction took %s", kind, elapsed)
+	}
+}
+
+// bbolt has no concept of a top-level key outside of any bucket, so keys
+// with no BucketSeparator are stored in a reserved bucket of their own.
+// Every real, user-supplied path segment is encoded with segmentTag
+// prepended (see encodeSegment) so that it lives in a namespace disjoint
+// from rootBucketSentinel: a user typing a segment equal to
+// rootBucketSentinel's raw bytes, or even to "_root" itself, still maps
+// to a distinct, correctly encoded bucket and can never alias the
+// reserved one.
+const (
+	segmentTag byte = 1
+	rootTag    byte = 0
+)
+
+// rootBucketSentinel is the bucket name used for keys with no
+// BucketSeparator. encodeSegment never produces it, since its output
+// always starts with segmentTag rather than rootTag.
+var rootBucketSentinel = []byte{rootTag}
+
+func encodeSegment(segment []byte) []byte {
+	encoded := make([]byte, 0, len(segment)+1)
+	encoded = append(encoded, segmentTag)
+	return append(encoded, segment...)
+}
+
+// splitKey splits key on BucketSeparator into the bucket path and the
+// final, innermost key. Path segments are encoded via encodeSegment; a
+// key with no separator resolves to rootBucketSentinel.
+func splitKey(key []byte) (path [][]byte, leaf []byte) {
+	segments := bytes.Split(key, []byte{BucketSeparator})
+	if len(segments) == 1 {
+		return [][]byte{rootBucketSentinel}, segments[0]
+	}
+	path = make([][]byte, len(segments)-1)
+	for i, segment := range segments[:len(segments)-1] {
+		path[i] = encodeSegment(segment)
+	}
+	return path, segments[len(segments)-1]
+}
+
+// bucketFor navigates tx down path, optionally creating missing buckets
+// along the way. It returns nil, nil if the bucket does not exist and
+// create is false.
+func bucketFor(tx *bolt.Tx, path [][]byte, create bool) (*bolt.Bucket, error) {
+	var bucket *bolt.Bucket
+	for i, name := range path {
+		if i == 0 {
+			if create {
+				b, err := tx.CreateBucketIfNotExists(name)
+				if err != nil {
+					return nil, err
+				}
+				bucket = b
+			} else {
+				bucket = tx.Bucket(name)
+			}
+		} else {
+			if create {
+				b, err := bucket.CreateBucketIfNotExists(name)
+				if err != nil {
+					return nil, err
+				}
+				bucket = b
+			} else {
+				bucket = bucket.Bucket(name)
+			}
+		}
+		if bucket == nil {
+			return nil, nil
+		}
+	}
+	return bucket, nil
+}
+
+func (s *implBoltStore) Get(key []byte) ([]byte, error) {
+	path, leaf := splitKey(key)
+	var value []byte
+	err := s.view(func(tx *bolt.Tx) error {
+		bucket, err := bucketFor(tx, path, false)
+		if err != nil || bucket == nil {
+			return err
+		}
+		if v := bucket.Get(leaf); v != nil {
+			// append([]byte{}, v...), not append([]byte(nil), v...): the
+			// latter returns nil for a zero-length v, making a stored
+			// empty value indistinguishable from a missing key.
+			value = append([]byte{}, v...)
+		}
+		return nil
+	})
+	return value, err
+}
+
+func (s *implBoltStore) Set(key, value []byte) error {
+	path, leaf := splitKey(key)
+	return s.update(func(tx *bolt.Tx) error {
+		bucket, err := bucketFor(tx, path, true)
+		if err != nil {
+			return err
+		}
+		return bucket.Put(leaf, value)
+	})
+}
+
+func (s *implBoltStore) CompareAndSwap(key, oldValue, newValue []byte) (bool, error) {
+	path, leaf := splitKey(key)
+	var swapped bool
+	err := s.update(func(tx *bolt.Tx) error {
+		bucket, err := bucketFor(tx, path, false)
+		if err != nil {
+			return err
+		}
+		var current []byte
+		if bucket != nil {
+			current = bucket.Get(leaf)
+		}
+		if !bytes.Equal(current, oldValue) {
+			return nil
+		}
+		swapped = true
+		if newValue == nil {
+			if bucket == nil {
+				return nil
+			}
+			return bucket.Delete(leaf)
+		}
+		if bucket == nil {
+			bucket, err = bucketFor(tx, path, true)
+			if err != nil {
+				return err
+			}
+		}
+		return bucket.Put(leaf, newValue)
+	})
+	return swapped, err
+}
+
+func (s *implBoltStore) Delete(key []byte) error {
+	path, leaf := splitKey(key)
+	return s.update(func(tx *bolt.Tx) error {
+		bucket, err := bucketFor(tx, path, false)
+		if err != nil || bucket == nil {
+			return err
+		}
+		return bucket.Delete(leaf)
+	})
+}
+
+func (s *implBoltStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,130 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package bboltstore
+
+import (
+	"path/filepath"
+	"testing"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func openTestDB(t *testing.T) *bolt.DB {
+	t.Helper()
+	db, err := bolt.Open(filepath.Join(t.TempDir(), "test.db"), 0600, nil)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestStoreGetSetDelete(t *testing.T) {
+	store := NewBoltStore(openTestDB(t))
+
+	if v, err := store.Get([]byte("users:42:name")); err != nil || v != nil {
+		t.Fatalf("expected missing key, got %q, %v", v, err)
+	}
+
+	if err := store.Set([]byte("users:42:name"), []byte("alice")); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	v, err := store.Get([]byte("users:42:name"))
+	if err != nil || string(v) != "alice" {
+		t.Fatalf("get: got %q, %v", v, err)
+	}
+
+	if err := store.Delete([]byte("users:42:name")); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if v, err := store.Get([]byte("users:42:name")); err != nil || v != nil {
+		t.Fatalf("expected deleted key to be gone, got %q, %v", v, err)
+	}
+}
+
+// TestStoreGetEmptyValue guards against an empty stored value being
+// confused with a missing key - both look like a nil, nil Get to a naive
+// nil-check against the underlying bbolt slice.
+func TestStoreGetEmptyValue(t *testing.T) {
+	store := NewBoltStore(openTestDB(t))
+
+	if err := store.Set([]byte("users:42:bio"), []byte{}); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	v, err := store.Get([]byte("users:42:bio"))
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if v == nil {
+		t.Fatalf("expected a non-nil empty value, got nil (indistinguishable from missing)")
+	}
+	if len(v) != 0 {
+		t.Fatalf("expected an empty value, got %q", v)
+	}
+}
+
+// TestStoreFlatKeyDoesNotAliasReservedBucket guards against the reserved
+// bucket used for separator-less keys aliasing a real, user-supplied
+// top-level bucket of the same name.
+func TestStoreFlatKeyDoesNotAliasReservedBucket(t *testing.T) {
+	store := NewBoltStore(openTestDB(t))
+
+	// A user segment equal to rootBucketSentinel's raw bytes, with the same
+	// leaf as the flat key, would overwrite it if the two buckets aliased.
+	if err := store.Set([]byte("\x00:flat"), []byte("a")); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if err := store.Set([]byte("flat"), []byte("b")); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	if v, err := store.Get([]byte("\x00:flat")); err != nil || string(v) != "a" {
+		t.Fatalf("get \\x00:flat: got %q, %v", v, err)
+	}
+	if v, err := store.Get([]byte("flat")); err != nil || string(v) != "b" {
+		t.Fatalf("get flat: got %q, %v", v, err)
+	}
+}
+
+func TestCompareAndSwap(t *testing.T) {
+	db := openTestDB(t)
+	store := NewBoltStore(db)
+
+	swapped, err := store.CompareAndSwap([]byte("a:b:c"), []byte("wrong"), []byte("new"))
+	if err != nil {
+		t.Fatalf("cas: %v", err)
+	}
+	if swapped {
+		t.Fatalf("expected cas to fail against a missing key")
+	}
+
+	// A failed compare must not leave behind empty buckets as a side effect.
+	err = db.View(func(tx *bolt.Tx) error {
+		if tx.Bucket(encodeSegment([]byte("a"))) != nil {
+			t.Fatalf("expected no bucket to have been created by a failed cas")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("view: %v", err)
+	}
+
+	swapped, err = store.CompareAndSwap([]byte("a:b:c"), nil, []byte("first"))
+	if err != nil || !swapped {
+		t.Fatalf("expected cas against a missing key with nil oldValue to succeed, got %v, %v", swapped, err)
+	}
+
+	swapped, err = store.CompareAndSwap([]byte("a:b:c"), []byte("first"), []byte("second"))
+	if err != nil || !swapped {
+		t.Fatalf("expected cas to succeed, got %v, %v", swapped, err)
+	}
+
+	if v, err := store.Get([]byte("a:b:c")); err != nil || string(v) != "second" {
+		t.Fatalf("get: got %q, %v", v, err)
+	}
+}
@@ -0,0 +1,155 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package bboltstore
+
+import (
+	"context"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Verifier runs on-demand consistency checks against a *bolt.DB. See
+// WithStrictMode for checking every write transaction as it commits
+// instead.
+type Verifier struct {
+	db     *bolt.DB
+	logger Logger
+}
+
+// VerifierOption configures a Verifier created by NewVerifier.
+type VerifierOption interface {
+	applyVerifier(*Verifier)
+}
+
+// NewVerifier creates a Verifier over db.
+func NewVerifier(db *bolt.DB, options ...VerifierOption) *Verifier {
+	v := &Verifier{db: db, logger: NopLogger}
+	for _, opt := range options {
+		opt.applyVerifier(v)
+	}
+	return v
+}
+
+// VerifyOptions controls a single Verify call.
+type VerifyOptions struct {
+
+	// Context cancels the verification early. ErrCanceled is returned as
+	// soon as the cancellation is observed.
+	Context context.Context
+}
+
+// BucketStructure describes one Bucket encountered while walking the
+// database during Verify.
+type BucketStructure struct {
+	Name     []byte
+	Depth    int
+	KeyN     int
+	Size     int64
+	Children []*BucketStructure
+}
+
+// VerifyReport is the result of a Verify call. Errors streams every
+// consistency error Tx.Check() finds, so a badly corrupt database with
+// millions of errors never has to be held in memory at once; it is
+// closed once verification completes. Buckets and Err are only safe to
+// read once Errors has been drained to closure - describing the shape of
+// the database regardless of whether any errors were found, and any
+// error that stopped verification early, respectively.
+type VerifyReport struct {
+	Errors  <-chan error
+	Buckets []*BucketStructure
+	Err     error
+}
+
+// Verify runs Tx.Check() inside a read-only transaction, forwarding its
+// errors over VerifyReport.Errors as they are found, and walks every
+// Bucket to build a BucketStructure tree once Tx.Check() is drained. The
+// read transaction, and the goroutine doing this work, stay alive until
+// the caller has fully drained Errors - ranging over it to completion is
+// required to release them. opts.Context is honored between buckets and
+// while handing errors to a slow or absent receiver; on cancellation,
+// Tx.Check()'s channel is still drained to completion (without being
+// forwarded) so its internal goroutine is not left blocked forever.
+func (v *Verifier) Verify(opts VerifyOptions) *VerifyReport {
+	errCh := make(chan error)
+	report := &VerifyReport{Errors: errCh}
+
+	go func() {
+		defer close(errCh)
+
+		if err := checkCanceled(opts.Context); err != nil {
+			report.Err = err
+			return
+		}
+
+		report.Err = v.db.View(func(tx *bolt.Tx) error {
+			canceled := false
+			for checkErr := range tx.Check() {
+				v.logger.Errorf("bboltstore: verify found corruption: %v", checkErr)
+				if canceled {
+					continue
+				}
+				select {
+				case errCh <- checkErr:
+				case <-doneChan(opts.Context):
+					canceled = true
+				}
+			}
+			if canceled {
+				return ErrCanceled
+			}
+
+			return tx.ForEach(func(name []byte, bucket *bolt.Bucket) error {
+				structure, err := walkBucketStructure(opts.Context, name, bucket, 0)
+				if err != nil {
+					return err
+				}
+				report.Buckets = append(report.Buckets, structure)
+				return nil
+			})
+		})
+	}()
+
+	return report
+}
+
+// doneChan is ctx.Done(), except that it tolerates a nil ctx (treated as
+// never canceled) the same way checkCanceled does, rather than panicking.
+func doneChan(ctx context.Context) <-chan struct{} {
+	if ctx == nil {
+		return nil
+	}
+	return ctx.Done()
+}
+
+func walkBucketStructure(ctx context.Context, name []byte, bucket *bolt.Bucket, depth int) (*BucketStructure, error) {
+	if err := checkCanceled(ctx); err != nil {
+		return nil, err
+	}
+
+	structure := &BucketStructure{
+		Name:  append([]byte(nil), name...),
+		Depth: depth,
+		KeyN:  bucket.Stats().KeyN,
+	}
+
+	err := bucket.ForEach(func(k, v []byte) error {
+		if v == nil {
+			child, err := walkBucketStructure(ctx, k, bucket.Bucket(k), depth+1)
+			if err != nil {
+				return err
+			}
+			structure.Children = append(structure.Children, child)
+			return nil
+		}
+		structure.Size += int64(len(k) + len(v))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return structure, nil
+}
@@ -0,0 +1,56 @@
+/*
+ * Copyright (c) 2023 Zander Schwid & Co. LLC.
+ * SPDX-License-Identifier: BUSL-1.1
+ */
+
+package bboltstore
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestVerifyCleanDatabase(t *testing.T) {
+	db := openTestDB(t)
+	store := NewBoltStore(db)
+	if err := store.Set([]byte("users:1:name"), []byte("alice")); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	report := NewVerifier(db).Verify(VerifyOptions{})
+
+	var errs []error
+	for err := range report.Errors {
+		errs = append(errs, err)
+	}
+	if len(errs) != 0 {
+		t.Fatalf("expected no corruption errors on a clean db, got %v", errs)
+	}
+	if report.Err != nil {
+		t.Fatalf("expected no error, got %v", report.Err)
+	}
+	if len(report.Buckets) != 1 ||
+		len(report.Buckets[0].Children) != 1 ||
+		!bytes.Equal(report.Buckets[0].Children[0].Name, encodeSegment([]byte("1"))) {
+		t.Fatalf("unexpected bucket structure: %+v", report.Buckets)
+	}
+}
+
+func TestVerifyHonorsCanceledContext(t *testing.T) {
+	db := openTestDB(t)
+	store := NewBoltStore(db)
+	if err := store.Set([]byte("users:1:name"), []byte("alice")); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	report := NewVerifier(db).Verify(VerifyOptions{Context: ctx})
+	for range report.Errors {
+	}
+	if report.Err != ErrCanceled {
+		t.Fatalf("expected ErrCanceled, got %v", report.Err)
+	}
+}